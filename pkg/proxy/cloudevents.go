@@ -0,0 +1,138 @@
+package proxy
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+//
+// Private types
+//
+
+// cloudEventAttributes holds the CloudEvents 1.0 context attributes derived
+// from an outbound Stripe webhook payload.
+type cloudEventAttributes struct {
+	ID      string
+	Source  string
+	Type    string
+	Time    string
+	Subject string
+}
+
+// cloudEventEnvelope is the `application/cloudevents+json` structured-mode
+// representation of a CloudEvent, with the original Stripe payload nested
+// under `data`.
+type cloudEventEnvelope struct {
+	SpecVersion     string          `json:"specversion"`
+	ID              string          `json:"id"`
+	Source          string          `json:"source"`
+	Type            string          `json:"type"`
+	Time            string          `json:"time,omitempty"`
+	Subject         string          `json:"subject,omitempty"`
+	DataContentType string          `json:"datacontenttype"`
+	Data            json.RawMessage `json:"data"`
+}
+
+//
+// Private functions
+//
+
+// applyCloudEvents rewrites req in place so that it carries the CloudEvents
+// 1.0 attributes derived from evtCtx, either as `ce-*` headers (binary mode)
+// or as a structured `application/cloudevents+json` body (structured mode).
+func applyCloudEvents(req *http.Request, evtCtx eventContext, mode CloudEventsMode) error {
+	attrs, err := newCloudEventAttributes(evtCtx)
+	if err != nil {
+		return err
+	}
+
+	if mode == CloudEventsStructured {
+		envelope := cloudEventEnvelope{
+			SpecVersion:     "1.0",
+			ID:              attrs.ID,
+			Source:          attrs.Source,
+			Type:            attrs.Type,
+			Time:            attrs.Time,
+			Subject:         attrs.Subject,
+			DataContentType: "application/json",
+			Data:            json.RawMessage(evtCtx.requestBody),
+		}
+
+		body, err := json.Marshal(envelope)
+		if err != nil {
+			return InvalidCloudEventError{Err: fmt.Errorf("encoding structured CloudEvent: %w", err)}
+		}
+
+		req.Body = io.NopCloser(bytes.NewBuffer(body))
+		req.ContentLength = int64(len(body))
+		req.Header.Set("Content-Type", "application/cloudevents+json")
+
+		return nil
+	}
+
+	req.Header.Set("ce-specversion", "1.0")
+	req.Header.Set("ce-id", attrs.ID)
+	req.Header.Set("ce-source", attrs.Source)
+	req.Header.Set("ce-type", attrs.Type)
+
+	if attrs.Time != "" {
+		req.Header.Set("ce-time", attrs.Time)
+	}
+
+	if attrs.Subject != "" {
+		req.Header.Set("ce-subject", attrs.Subject)
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	return nil
+}
+
+// newCloudEventAttributes derives the CloudEvents 1.0 context attributes
+// from the raw Stripe event payload carried on evtCtx, returning an
+// InvalidCloudEventError if the payload can't be parsed or is missing an
+// attribute CloudEvents requires.
+func newCloudEventAttributes(evtCtx eventContext) (*cloudEventAttributes, error) {
+	var stripeEvent struct {
+		ID      string `json:"id"`
+		Type    string `json:"type"`
+		Created int64  `json:"created"`
+		Account string `json:"account"`
+		Data    struct {
+			Object struct {
+				ID string `json:"id"`
+			} `json:"object"`
+		} `json:"data"`
+	}
+
+	if err := json.Unmarshal([]byte(evtCtx.requestBody), &stripeEvent); err != nil {
+		return nil, InvalidCloudEventError{Err: fmt.Errorf("decoding Stripe event payload: %w", err)}
+	}
+
+	if stripeEvent.ID == "" || stripeEvent.Type == "" {
+		return nil, InvalidCloudEventError{Err: errors.New("stripe event payload is missing a required \"id\" or \"type\" field")}
+	}
+
+	source := "/stripe"
+	if stripeEvent.Account != "" {
+		source = "/stripe/" + stripeEvent.Account
+	}
+
+	attrs := &cloudEventAttributes{
+		ID:      stripeEvent.ID,
+		Source:  source,
+		Type:    "com.stripe.v1." + stripeEvent.Type,
+		Subject: stripeEvent.Data.Object.ID,
+	}
+
+	if stripeEvent.Created != 0 {
+		attrs.Time = time.Unix(stripeEvent.Created, 0).UTC().Format(time.RFC3339)
+	}
+
+	return attrs, nil
+}
@@ -0,0 +1,143 @@
+package proxy
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestNewCloudEventAttributes(t *testing.T) {
+	t.Run("derives attributes from a Connect event", func(t *testing.T) {
+		evtCtx := eventContext{requestBody: `{
+			"id": "evt_123",
+			"type": "charge.succeeded",
+			"created": 1700000000,
+			"account": "acct_456",
+			"data": {"object": {"id": "ch_789"}}
+		}`}
+
+		attrs, err := newCloudEventAttributes(evtCtx)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if attrs.ID != "evt_123" {
+			t.Errorf("ID = %q, want %q", attrs.ID, "evt_123")
+		}
+
+		if attrs.Source != "/stripe/acct_456" {
+			t.Errorf("Source = %q, want %q", attrs.Source, "/stripe/acct_456")
+		}
+
+		if attrs.Type != "com.stripe.v1.charge.succeeded" {
+			t.Errorf("Type = %q, want %q", attrs.Type, "com.stripe.v1.charge.succeeded")
+		}
+
+		if attrs.Subject != "ch_789" {
+			t.Errorf("Subject = %q, want %q", attrs.Subject, "ch_789")
+		}
+
+		if attrs.Time == "" {
+			t.Error("expected a non-empty Time")
+		}
+	})
+
+	t.Run("defaults source when there is no connected account", func(t *testing.T) {
+		evtCtx := eventContext{requestBody: `{"id": "evt_1", "type": "customer.created"}`}
+
+		attrs, err := newCloudEventAttributes(evtCtx)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		if attrs.Source != "/stripe" {
+			t.Errorf("Source = %q, want %q", attrs.Source, "/stripe")
+		}
+	})
+
+	t.Run("rejects a payload missing id or type", func(t *testing.T) {
+		evtCtx := eventContext{requestBody: `{"type": "customer.created"}`}
+
+		if _, err := newCloudEventAttributes(evtCtx); !isInvalidCloudEventError(err) {
+			t.Errorf("expected an InvalidCloudEventError, got %v", err)
+		}
+	})
+
+	t.Run("rejects malformed JSON", func(t *testing.T) {
+		evtCtx := eventContext{requestBody: `not json`}
+
+		if _, err := newCloudEventAttributes(evtCtx); !isInvalidCloudEventError(err) {
+			t.Errorf("expected an InvalidCloudEventError, got %v", err)
+		}
+	})
+}
+
+func TestApplyCloudEventsBinary(t *testing.T) {
+	evtCtx := eventContext{requestBody: `{"id": "evt_123", "type": "charge.succeeded", "created": 1700000000}`}
+
+	req, err := http.NewRequest(http.MethodPost, "http://localhost", strings.NewReader(evtCtx.requestBody))
+	if err != nil {
+		t.Fatalf("unexpected error building request: %v", err)
+	}
+
+	if err := applyCloudEvents(req, evtCtx, CloudEventsBinary); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := req.Header.Get("ce-specversion"); got != "1.0" {
+		t.Errorf("ce-specversion = %q, want %q", got, "1.0")
+	}
+
+	if got := req.Header.Get("ce-id"); got != "evt_123" {
+		t.Errorf("ce-id = %q, want %q", got, "evt_123")
+	}
+
+	if got := req.Header.Get("Content-Type"); got != "application/json" {
+		t.Errorf("Content-Type = %q, want %q", got, "application/json")
+	}
+}
+
+func TestApplyCloudEventsStructured(t *testing.T) {
+	evtCtx := eventContext{requestBody: `{"id": "evt_123", "type": "charge.succeeded"}`}
+
+	req, err := http.NewRequest(http.MethodPost, "http://localhost", strings.NewReader(evtCtx.requestBody))
+	if err != nil {
+		t.Fatalf("unexpected error building request: %v", err)
+	}
+
+	if err := applyCloudEvents(req, evtCtx, CloudEventsStructured); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got := req.Header.Get("Content-Type"); got != "application/cloudevents+json" {
+		t.Errorf("Content-Type = %q, want %q", got, "application/cloudevents+json")
+	}
+
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		t.Fatalf("unexpected error reading body: %v", err)
+	}
+
+	if !strings.Contains(string(body), `"specversion":"1.0"`) {
+		t.Errorf("expected structured body to carry specversion, got %s", body)
+	}
+}
+
+func TestApplyCloudEventsRejectsInvalidPayload(t *testing.T) {
+	evtCtx := eventContext{requestBody: `{"type": "customer.created"}`}
+
+	req, err := http.NewRequest(http.MethodPost, "http://localhost", strings.NewReader(evtCtx.requestBody))
+	if err != nil {
+		t.Fatalf("unexpected error building request: %v", err)
+	}
+
+	if err := applyCloudEvents(req, evtCtx, CloudEventsBinary); !isInvalidCloudEventError(err) {
+		t.Errorf("expected an InvalidCloudEventError, got %v", err)
+	}
+}
+
+func isInvalidCloudEventError(err error) bool {
+	_, ok := err.(InvalidCloudEventError)
+	return ok
+}
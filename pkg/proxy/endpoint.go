@@ -2,6 +2,8 @@ package proxy
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"io"
 	"net/http"
 	"regexp"
@@ -23,12 +25,76 @@ type EndpointConfig struct {
 
 	Log *log.Logger
 
-	ResponseHandler EndpointResponseHandler
+	// ResponseHandlers are invoked in order once a response is received
+	// from the local endpoint, e.g. to log it or record metrics about it.
+	ResponseHandlers []EndpointResponseHandler
+
+	// RequestMiddleware are invoked in order on the outgoing request after
+	// custom headers (and any CloudEvents transform) have been applied, but
+	// before it is sent via HTTPClient.Do. This lets callers bolt on things
+	// like request signing, mTLS client-cert selection, or tracing header
+	// injection without forking the CLI.
+	RequestMiddleware []func(*http.Request) (*http.Request, error)
+
+	// MaxRetries is the maximum number of additional attempts made to
+	// forward a webhook after the initial attempt fails with a network
+	// error or a retryable HTTP status. Zero (the default) disables
+	// retries.
+	MaxRetries int
+
+	// InitialBackoff is the base delay before the first retry. Defaults to
+	// defaultInitialBackoff when MaxRetries > 0 and InitialBackoff is zero.
+	InitialBackoff time.Duration
+
+	// MaxBackoff caps the exponential delay between retries before jitter
+	// is applied; the jitter factor in [0.5, 1.5) can stretch a single delay
+	// to up to 1.5x MaxBackoff, and a delay taken from a Retry-After header
+	// is not capped. Defaults to defaultMaxBackoff when MaxRetries > 0 and
+	// MaxBackoff is zero.
+	MaxBackoff time.Duration
+
+	// RetryableStatus reports whether an HTTP response status should be
+	// retried. Defaults to defaultRetryableStatus, which retries 408, 425,
+	// 429, 500, 502, 503, and 504.
+	RetryableStatus func(int) bool
+
+	// RequestTimeout bounds how long a single attempt to forward to the
+	// local endpoint may take, overriding HTTPClient.Timeout for that
+	// attempt. It is applied freshly to each retry, so it bounds a single
+	// attempt rather than the retry sequence as a whole — a forward with
+	// MaxRetries > 0 can still take up to roughly
+	// (MaxRetries + 1) * RequestTimeout plus the backoff between attempts
+	// in total. Zero means no
+	// per-attempt deadline is applied beyond whatever the caller's context
+	// and HTTPClient.Timeout already impose.
+	RequestTimeout time.Duration
+
+	// CloudEvents controls whether outbound webhook requests are transformed
+	// into CloudEvents 1.0 HTTP messages before being forwarded. Defaults to
+	// CloudEventsOff, which forwards the Stripe webhook unmodified.
+	CloudEvents CloudEventsMode
 
 	// OutCh is the channel to send data and statuses to for processing in other packages
 	OutCh chan websocket.IElement
 }
 
+// CloudEventsMode selects how (or whether) outbound webhook requests are
+// encoded as CloudEvents 1.0 HTTP messages.
+type CloudEventsMode int
+
+const (
+	// CloudEventsOff forwards the Stripe webhook request unmodified.
+	CloudEventsOff CloudEventsMode = iota
+	// CloudEventsBinary forwards the Stripe webhook using the CloudEvents
+	// binary content mode: CloudEvents attributes become `ce-*` headers and
+	// the original Stripe payload is left as the HTTP body.
+	CloudEventsBinary
+	// CloudEventsStructured forwards the Stripe webhook using the
+	// CloudEvents structured content mode: attributes and data are both
+	// encoded into a single `application/cloudevents+json` body.
+	CloudEventsStructured
+)
+
 // EndpointResponseHandler handles a response from the endpoint.
 type EndpointResponseHandler interface {
 	ProcessResponse(eventContext, string, *http.Response)
@@ -54,6 +120,39 @@ func (f FailedToPostError) Error() string {
 	return f.Err.Error()
 }
 
+// InvalidCloudEventError describes a failure to construct a valid CloudEvents
+// 1.0 message from an incoming Stripe webhook payload, usually because the
+// payload is missing an attribute CloudEvents requires (e.g. `id` or `type`).
+type InvalidCloudEventError struct {
+	Err error
+}
+
+func (e InvalidCloudEventError) Error() string {
+	return e.Err.Error()
+}
+
+// RequestCanceledError describes a forward to the local endpoint that was
+// aborted because its context was canceled, e.g. the user interrupted
+// `stripe listen` before the request completed.
+type RequestCanceledError struct {
+	Err error
+}
+
+func (e RequestCanceledError) Error() string {
+	return e.Err.Error()
+}
+
+// RequestTimedOutError describes an attempt to forward to the local endpoint
+// that did not complete within its deadline (EndpointConfig.RequestTimeout
+// applied to that attempt, or a deadline set on the caller's context).
+type RequestTimedOutError struct {
+	Err error
+}
+
+func (e RequestTimedOutError) Error() string {
+	return e.Err.Error()
+}
+
 // EndpointClient is the client used to POST webhook requests to the local endpoint.
 type EndpointClient struct {
 	// URL the client sends POST requests to
@@ -96,77 +195,212 @@ func (c *EndpointClient) SupportsContext(context string) bool {
 	return context == ""
 }
 
-// Post sends a message to the local endpoint.
+// Post sends a message to the local endpoint. It is a thin wrapper around
+// PostWithContext using context.Background().
 func (c *EndpointClient) Post(evtCtx eventContext) error {
+	return c.PostWithContext(context.Background(), evtCtx)
+}
+
+// PostWithContext sends a message to the local endpoint, aborting the
+// in-flight request if ctx is canceled or its deadline expires. If
+// EndpointConfig.RequestTimeout is set, it bounds each individual attempt
+// (including retries) regardless of any deadline already present on ctx.
+func (c *EndpointClient) PostWithContext(ctx context.Context, evtCtx eventContext) error {
+	_, err := c.Send(ctx, evtCtx)
+	return err
+}
+
+// Send implements Forwarder for EndpointClient: it forwards evtCtx over
+// HTTP, applying the client's retry policy, CloudEvents transform, and
+// request/response middleware chains.
+func (c *EndpointClient) Send(ctx context.Context, evtCtx eventContext) (Response, error) {
 	c.cfg.Log.WithFields(log.Fields{
 		"prefix": "proxy.EndpointClient.Post",
 	}).Debug("Forwarding event to local endpoint")
 
-	req, err := http.NewRequest(http.MethodPost, c.URL, bytes.NewBuffer([]byte(evtCtx.requestBody)))
+	resp, cancel, err := c.sendWithRetry(ctx, evtCtx, c.cfg.HTTPClient)
+	defer cancel()
+
 	if err != nil {
-		return err
+		c.cfg.OutCh <- websocket.ErrorElement{
+			Error: classifyPostError(err),
+		}
+		return Response{}, err
 	}
 
-	for k, v := range evtCtx.requestHeaders {
-		req.Header.Add(k, v)
-	}
+	defer resp.Body.Close()
 
-	// add custom headers
-	for k, v := range c.headers {
-		if strings.ToLower(k) == "host" {
-			req.Host = v
-		} else {
-			req.Header.Add(k, v)
-		}
-	}
+	c.processResponse(evtCtx, resp)
+
+	return Response{
+		Status: Status{Code: resp.StatusCode, Detail: resp.Status},
+		Raw:    resp,
+	}, nil
+}
+
+// PostV2 sends a message to a local event destination. It is a thin wrapper
+// around PostV2WithContext using context.Background().
+func (c *EndpointClient) PostV2(evtCtx eventContext) error {
+	return c.PostV2WithContext(context.Background(), evtCtx)
+}
+
+// PostV2WithContext sends a message to a local event destination, aborting
+// the in-flight request if ctx is canceled or its deadline expires.
+func (c *EndpointClient) PostV2WithContext(ctx context.Context, evtCtx eventContext) error {
+	resp, cancel, err := c.sendWithRetry(ctx, evtCtx, http.DefaultClient)
+	defer cancel()
 
-	resp, err := c.cfg.HTTPClient.Do(req)
 	if err != nil {
 		c.cfg.OutCh <- websocket.ErrorElement{
-			Error: FailedToPostError{Err: err},
+			Error: classifyPostError(err),
 		}
 		return err
 	}
-
 	defer resp.Body.Close()
 
-	c.cfg.ResponseHandler.ProcessResponse(evtCtx, c.URL, resp)
+	c.processResponse(evtCtx, resp)
 
 	return nil
 }
 
-// PostV2 sends a message to a local event destination
-func (c *EndpointClient) PostV2(evtCtx eventContext) error {
-	req, err := http.NewRequest(http.MethodPost, c.URL, bytes.NewBuffer([]byte(evtCtx.requestBody)))
-	if err != nil {
-		return err
+// Close implements Forwarder for EndpointClient. HTTP connections are
+// pooled and reused by HTTPClient's own transport, so there is nothing for
+// EndpointClient itself to release.
+func (c *EndpointClient) Close() error {
+	return nil
+}
+
+// processResponse runs resp through each configured ResponseHandlers entry
+// in order.
+func (c *EndpointClient) processResponse(evtCtx eventContext, resp *http.Response) {
+	for _, h := range c.cfg.ResponseHandlers {
+		h.ProcessResponse(evtCtx, c.URL, resp)
 	}
+}
 
-	for k, v := range evtCtx.requestHeaders {
-		req.Header.Add(k, v)
+// sendWithRetry builds and sends a POST request for evtCtx using client,
+// retrying on network errors and retryable HTTP statuses per
+// EndpointConfig's retry policy. Each attempt re-applies headers and any
+// configured CloudEvents transform to a freshly built request, since a
+// request's body can't be replayed once consumed, and gets its own
+// EndpointConfig.RequestTimeout deadline so the timeout bounds a single
+// attempt rather than the whole retry sequence.
+//
+// The returned context.CancelFunc releases resources tied to the attempt
+// that produced the returned response (or the last attempted one, on
+// failure) and must be called once the caller is done with that response,
+// e.g. via a deferred call right after sendWithRetry returns.
+func (c *EndpointClient) sendWithRetry(ctx context.Context, evtCtx eventContext, client *http.Client) (*http.Response, context.CancelFunc, error) {
+	bodyBytes := []byte(evtCtx.requestBody)
+
+	// A configured RequestTimeout replaces the client's own Timeout, so a
+	// per-attempt deadline longer than HTTPClient.Timeout is not cut short.
+	if c.cfg.RequestTimeout > 0 && client.Timeout > 0 {
+		overridden := *client
+		overridden.Timeout = 0
+		client = &overridden
 	}
 
-	// add custom headers
-	for k, v := range c.headers {
-		if strings.ToLower(k) == "host" {
-			req.Host = v
-		} else {
+	for attempt := 0; ; attempt++ {
+		attemptCtx, cancel := c.withRequestTimeout(ctx)
+
+		req, err := http.NewRequestWithContext(attemptCtx, http.MethodPost, c.URL, bytes.NewReader(bodyBytes))
+		if err != nil {
+			cancel()
+			return nil, func() {}, err
+		}
+
+		for k, v := range evtCtx.requestHeaders {
 			req.Header.Add(k, v)
 		}
-	}
 
-	resp, err := http.DefaultClient.Do(req)
-	if err != nil {
-		c.cfg.OutCh <- websocket.ErrorElement{
-			Error: FailedToPostError{Err: err},
+		// add custom headers
+		for k, v := range c.headers {
+			if strings.ToLower(k) == "host" {
+				req.Host = v
+			} else {
+				req.Header.Add(k, v)
+			}
+		}
+
+		if attempt > 0 {
+			if key := idempotencyKey(evtCtx); key != "" {
+				req.Header.Set("Idempotency-Key", key)
+			}
+		}
+
+		if c.cfg.CloudEvents != CloudEventsOff {
+			if err := applyCloudEvents(req, evtCtx, c.cfg.CloudEvents); err != nil {
+				cancel()
+				return nil, func() {}, err
+			}
+		}
+
+		for _, mw := range c.cfg.RequestMiddleware {
+			req, err = mw(req)
+			if err != nil {
+				cancel()
+				return nil, func() {}, err
+			}
+		}
+
+		resp, doErr := client.Do(req)
+
+		retry, wait := c.shouldRetryPost(ctx, attempt, resp, doErr)
+		if !retry {
+			return resp, cancel, doErr
+		}
+
+		if resp != nil {
+			resp.Body.Close()
+		}
+		cancel()
+
+		c.cfg.OutCh <- websocket.RetryElement{
+			Attempt: attempt + 1,
+			Backoff: wait,
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, func() {}, ctx.Err()
+		case <-time.After(wait):
 		}
-		return err
 	}
-	defer resp.Body.Close()
+}
 
-	c.cfg.ResponseHandler.ProcessResponse(evtCtx, c.URL, resp)
+// withRequestTimeout returns a derived context bounded by
+// EndpointConfig.RequestTimeout, or ctx unchanged (with a no-op cancel) if
+// no per-attempt timeout is configured.
+func (c *EndpointClient) withRequestTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.cfg.RequestTimeout <= 0 {
+		return ctx, func() {}
+	}
 
-	return nil
+	return context.WithTimeout(ctx, c.cfg.RequestTimeout)
+}
+
+// classifyPostError maps a failed send to a typed error so the UI can
+// distinguish a canceled or timed-out forward from a generic connection
+// failure. It inspects err itself, rather than a context, since err may
+// carry the deadline/cancellation of any one retry attempt rather than the
+// overall request. Errors that are already typed by an earlier stage (e.g.
+// InvalidCloudEventError from applyCloudEvents) are passed through
+// unchanged rather than being rewrapped as a generic FailedToPostError.
+func classifyPostError(err error) error {
+	var invalidCloudEvent InvalidCloudEventError
+	if errors.As(err, &invalidCloudEvent) {
+		return err
+	}
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		return RequestCanceledError{Err: err}
+	case errors.Is(err, context.DeadlineExceeded):
+		return RequestTimedOutError{Err: err}
+	default:
+		return FailedToPostError{Err: err}
+	}
 }
 
 //
@@ -192,8 +426,22 @@ func NewEndpointClient(url string, headers []string, connect bool, events []stri
 		}
 	}
 
-	if cfg.ResponseHandler == nil {
-		cfg.ResponseHandler = EndpointResponseHandlerFunc(func(eventContext, string, *http.Response) {})
+	if len(cfg.ResponseHandlers) == 0 {
+		cfg.ResponseHandlers = []EndpointResponseHandler{
+			EndpointResponseHandlerFunc(func(eventContext, string, *http.Response) {}),
+		}
+	}
+
+	if cfg.InitialBackoff <= 0 {
+		cfg.InitialBackoff = defaultInitialBackoff
+	}
+
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = defaultMaxBackoff
+	}
+
+	if cfg.RetryableStatus == nil {
+		cfg.RetryableStatus = defaultRetryableStatus
 	}
 
 	return &EndpointClient{
@@ -211,7 +459,9 @@ func NewEndpointClient(url string, headers []string, connect bool, events []stri
 //
 
 const (
-	defaultTimeout = 30 * time.Second
+	defaultTimeout        = 30 * time.Second
+	defaultInitialBackoff = 200 * time.Millisecond
+	defaultMaxBackoff     = 10 * time.Second
 )
 
 //
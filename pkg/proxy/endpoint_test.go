@@ -0,0 +1,142 @@
+package proxy
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stripe/stripe-cli/pkg/websocket"
+)
+
+func TestSendWithRetryAppliesRequestTimeoutPerAttempt(t *testing.T) {
+	var attempts int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		time.Sleep(50 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	outCh := make(chan websocket.IElement, 10)
+
+	client := NewEndpointClient(srv.URL, nil, false, []string{"*"}, false, &EndpointConfig{
+		MaxRetries:     2,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     time.Millisecond,
+		RequestTimeout: 10 * time.Millisecond,
+		OutCh:          outCh,
+	})
+
+	evtCtx := eventContext{requestBody: `{"id":"evt_123","type":"charge.succeeded"}`}
+
+	err := client.PostWithContext(context.Background(), evtCtx)
+	if err == nil {
+		t.Fatal("expected every attempt to exceed its per-attempt RequestTimeout")
+	}
+
+	// A per-attempt RequestTimeout must not eat the whole retry budget: the
+	// client should still make MaxRetries+1 attempts, each bounded
+	// individually, rather than giving up after the first timeout.
+	if got := atomic.LoadInt32(&attempts); got != int32(client.cfg.MaxRetries+1) {
+		t.Fatalf("attempts = %d, want %d", got, client.cfg.MaxRetries+1)
+	}
+
+	// Each retry is announced with a RetryElement before the final error.
+	for i := 0; i < client.cfg.MaxRetries; i++ {
+		select {
+		case el := <-outCh:
+			if _, ok := el.(websocket.RetryElement); !ok {
+				t.Fatalf("element %d: expected a RetryElement, got %T", i, el)
+			}
+		default:
+			t.Fatalf("expected %d RetryElements on OutCh, got %d", client.cfg.MaxRetries, i)
+		}
+	}
+
+	select {
+	case el := <-outCh:
+		errEl, ok := el.(websocket.ErrorElement)
+		if !ok {
+			t.Fatalf("expected an ErrorElement, got %T", el)
+		}
+
+		if _, ok := errEl.Error.(RequestTimedOutError); !ok {
+			t.Fatalf("expected a RequestTimedOutError, got %T", errEl.Error)
+		}
+	default:
+		t.Fatal("expected an error to be reported on OutCh")
+	}
+}
+
+func TestPostWithContextReportsCancellation(t *testing.T) {
+	unblock := make(chan struct{})
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-unblock
+	}))
+	defer srv.Close()
+	// Release the handler before srv.Close, which waits for it to return.
+	defer close(unblock)
+
+	outCh := make(chan websocket.IElement, 1)
+
+	client := NewEndpointClient(srv.URL, nil, false, []string{"*"}, false, &EndpointConfig{OutCh: outCh})
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan error, 1)
+	go func() {
+		done <- client.PostWithContext(ctx, eventContext{requestBody: `{"id":"evt_123","type":"charge.succeeded"}`})
+	}()
+
+	cancel()
+
+	if err := <-done; err == nil {
+		t.Fatal("expected canceling ctx to abort the in-flight request")
+	}
+
+	errEl, ok := (<-outCh).(websocket.ErrorElement)
+	if !ok {
+		t.Fatalf("expected an ErrorElement on OutCh")
+	}
+
+	if _, ok := errEl.Error.(RequestCanceledError); !ok {
+		t.Fatalf("expected a RequestCanceledError, got %T", errEl.Error)
+	}
+}
+
+func TestPostWithContextReportsDeadlineExceeded(t *testing.T) {
+	unblock := make(chan struct{})
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-unblock
+	}))
+	defer srv.Close()
+	// Release the handler before srv.Close, which waits for it to return.
+	defer close(unblock)
+
+	outCh := make(chan websocket.IElement, 1)
+
+	client := NewEndpointClient(srv.URL, nil, false, []string{"*"}, false, &EndpointConfig{OutCh: outCh})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err := client.PostWithContext(ctx, eventContext{requestBody: `{"id":"evt_123","type":"charge.succeeded"}`})
+	if err == nil {
+		t.Fatal("expected the context deadline to abort the in-flight request")
+	}
+
+	errEl, ok := (<-outCh).(websocket.ErrorElement)
+	if !ok {
+		t.Fatalf("expected an ErrorElement on OutCh")
+	}
+
+	if _, ok := errEl.Error.(RequestTimedOutError); !ok {
+		t.Fatalf("expected a RequestTimedOutError, got %T", errEl.Error)
+	}
+}
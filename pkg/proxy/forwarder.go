@@ -0,0 +1,106 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+)
+
+//
+// Public types
+//
+
+// Forwarder sends a Stripe event to a forwarding destination, independent of
+// the underlying transport. EndpointClient implements Forwarder for HTTP(S)
+// destinations; GRPCForwarder and NATSForwarder implement it for gRPC and
+// NATS destinations respectively.
+type Forwarder interface {
+	Send(ctx context.Context, evtCtx eventContext) (Response, error)
+
+	// Close releases any connection the Forwarder holds open across Send
+	// calls. It is safe to call even if no connection was ever opened.
+	Close() error
+}
+
+// Status is a forwarder-agnostic summary of how a forwarded event was
+// received by its destination.
+type Status struct {
+	// Code is the destination's status code: an HTTP status for HTTP
+	// destinations, or 0 for destinations with no such concept (e.g. NATS,
+	// which is fire-and-forget).
+	Code int
+
+	// Detail is a short, scheme-specific description of the outcome, e.g.
+	// an HTTP status text or a gRPC status message.
+	Detail string
+}
+
+// Response is what a Forwarder returns after sending an event to its
+// destination.
+type Response struct {
+	Status Status
+
+	// Raw is the scheme-specific response (e.g. *http.Response for HTTP
+	// destinations). It is nil for destinations without one.
+	Raw interface{}
+}
+
+// EventPayloadDecodeError describes a Forwarder's failure to decode the
+// Stripe event JSON carried in a request's body, independent of which
+// destination scheme (gRPC, NATS, ...) was attempting the decode.
+type EventPayloadDecodeError struct {
+	Err error
+}
+
+func (e EventPayloadDecodeError) Error() string {
+	return e.Err.Error()
+}
+
+//
+// Private functions
+//
+
+// stripeEventIDAndType decodes evtCtx's raw body just far enough to recover
+// the Stripe event's id and type, the two fields every Forwarder needs to
+// tag a forwarded event with (e.g. as NATS message headers or CloudEvents
+// attributes), returning an EventPayloadDecodeError if the body isn't valid
+// Stripe event JSON.
+func stripeEventIDAndType(evtCtx eventContext) (id, eventType string, err error) {
+	var stripeEvent struct {
+		ID   string `json:"id"`
+		Type string `json:"type"`
+	}
+
+	if err := json.Unmarshal([]byte(evtCtx.requestBody), &stripeEvent); err != nil {
+		return "", "", EventPayloadDecodeError{Err: fmt.Errorf("decoding Stripe event payload: %w", err)}
+	}
+
+	return stripeEvent.ID, stripeEvent.Type, nil
+}
+
+//
+// Public functions
+//
+
+// NewForwarder returns the Forwarder appropriate for rawURL's scheme:
+// http:// and https:// (or no scheme, for backwards compatibility) dispatch
+// to an HTTP EndpointClient, grpc:// to a GRPCForwarder, and nats:// to a
+// NATSForwarder.
+func NewForwarder(rawURL string, headers []string, connect bool, events []string, isEventDestination bool, cfg *EndpointConfig) (Forwarder, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("parsing forward-to URI %q: %w", rawURL, err)
+	}
+
+	switch u.Scheme {
+	case "", "http", "https":
+		return NewEndpointClient(rawURL, headers, connect, events, isEventDestination, cfg), nil
+	case "grpc":
+		return NewGRPCForwarder(rawURL, cfg)
+	case "nats":
+		return NewNATSForwarder(rawURL, cfg)
+	default:
+		return nil, fmt.Errorf("unsupported forward-to scheme %q", u.Scheme)
+	}
+}
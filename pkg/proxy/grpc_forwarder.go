@@ -0,0 +1,238 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/jhump/protoreflect/desc"
+	"github.com/jhump/protoreflect/dynamic"
+	"github.com/jhump/protoreflect/grpcreflect"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+
+	"github.com/stripe/stripe-cli/pkg/websocket"
+)
+
+// GRPCForwarder sends Stripe events to a gRPC service, resolved from a
+// `grpc://host:port/pkg.Service/Method` forward-to URI. The target method's
+// request type is resolved via gRPC server reflection the first time Send
+// is called, so no generated client for the destination service is
+// required; its fields are populated from the StripeEvent schema in
+// rpc/stripeevent. The connection and resolved method are cached and reused
+// across subsequent Send calls.
+type GRPCForwarder struct {
+	target  string
+	service string
+	method  string
+
+	cfg *EndpointConfig
+
+	mu         sync.Mutex
+	conn       *grpc.ClientConn
+	methodDesc *desc.MethodDescriptor
+}
+
+// NewGRPCForwarder returns a Forwarder that invokes a unary gRPC method
+// named by the given `grpc://` forward-to URI.
+func NewGRPCForwarder(rawURL string, cfg *EndpointConfig) (*GRPCForwarder, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("parsing gRPC forward-to URI: %w", err)
+	}
+
+	service, method, err := splitGRPCMethod(u.Path)
+	if err != nil {
+		return nil, fmt.Errorf("gRPC forward-to URI %q: %w", rawURL, err)
+	}
+
+	if cfg == nil {
+		cfg = &EndpointConfig{}
+	}
+
+	return &GRPCForwarder{
+		target:  u.Host,
+		service: service,
+		method:  method,
+		cfg:     cfg,
+	}, nil
+}
+
+// Send marshals evtCtx into a StripeEvent message and invokes it against the
+// gRPC method named in g, reusing the dialed connection and resolved method
+// descriptor across calls.
+func (g *GRPCForwarder) Send(ctx context.Context, evtCtx eventContext) (Response, error) {
+	conn, methodDesc, err := g.connection(ctx)
+	if err != nil {
+		g.reportError(err)
+		return Response{}, err
+	}
+
+	req := dynamic.NewMessage(methodDesc.GetInputType())
+	if err := populateStripeEventFields(req, evtCtx); err != nil {
+		g.reportError(err)
+		return Response{}, err
+	}
+
+	resp := dynamic.NewMessage(methodDesc.GetOutputType())
+
+	fullMethod := fmt.Sprintf("/%s/%s", g.service, g.method)
+	if err := conn.Invoke(ctx, fullMethod, req, resp); err != nil {
+		wrapped := FailedToPostError{Err: err}
+		g.reportError(wrapped)
+		return Response{}, wrapped
+	}
+
+	return Response{
+		Status: Status{Detail: "OK"},
+		Raw:    resp,
+	}, nil
+}
+
+// connection returns g's dialed connection and resolved method descriptor,
+// establishing and resolving them on the first call and reusing them on
+// every subsequent one.
+func (g *GRPCForwarder) connection(ctx context.Context) (*grpc.ClientConn, *desc.MethodDescriptor, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.conn != nil && g.methodDesc != nil {
+		return g.conn, g.methodDesc, nil
+	}
+
+	conn, err := grpc.DialContext(ctx, g.target, grpc.WithTransportCredentials(insecure.NewCredentials()), grpc.WithBlock())
+	if err != nil {
+		return nil, nil, FailedToPostError{Err: fmt.Errorf("dialing gRPC target %q: %w", g.target, err)}
+	}
+
+	refClient := grpcreflect.NewClientAuto(ctx, conn)
+	defer refClient.Reset()
+
+	svcDesc, err := refClient.ResolveService(g.service)
+	if err != nil {
+		conn.Close()
+		return nil, nil, FailedToPostError{Err: fmt.Errorf("resolving gRPC service %q via reflection: %w", g.service, err)}
+	}
+
+	methodDesc := svcDesc.FindMethodByName(g.method)
+	if methodDesc == nil {
+		conn.Close()
+		return nil, nil, FailedToPostError{Err: fmt.Errorf("gRPC service %q has no method %q", g.service, g.method)}
+	}
+
+	g.conn = conn
+	g.methodDesc = methodDesc
+
+	return g.conn, g.methodDesc, nil
+}
+
+// Close implements Forwarder for GRPCForwarder, closing the cached
+// connection if one was ever established.
+func (g *GRPCForwarder) Close() error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.conn == nil {
+		return nil
+	}
+
+	err := g.conn.Close()
+	g.conn = nil
+	g.methodDesc = nil
+
+	return err
+}
+
+// reportError pushes err to g.cfg.OutCh so a failed gRPC forward is visible
+// to the UI the same way a failed HTTP forward is.
+func (g *GRPCForwarder) reportError(err error) {
+	if g.cfg.OutCh == nil {
+		return
+	}
+
+	g.cfg.OutCh <- websocket.ErrorElement{Error: err}
+}
+
+// populateStripeEventFields sets req's event_id, event_type, and payload
+// fields (per rpc/stripeevent/stripe_event.proto) from evtCtx, plus created
+// and headers when the destination message defines them. It returns an
+// UnsupportedEventSchemaError rather than silently dropping data if the
+// destination message type is missing any of the required fields.
+func populateStripeEventFields(req *dynamic.Message, evtCtx eventContext) error {
+	var stripeEvent struct {
+		ID      string `json:"id"`
+		Type    string `json:"type"`
+		Created int64  `json:"created"`
+	}
+
+	if err := json.Unmarshal([]byte(evtCtx.requestBody), &stripeEvent); err != nil {
+		return EventPayloadDecodeError{Err: fmt.Errorf("decoding Stripe event payload: %w", err)}
+	}
+
+	required := []struct {
+		name  string
+		value interface{}
+	}{
+		{"event_id", stripeEvent.ID},
+		{"event_type", stripeEvent.Type},
+		{"payload", []byte(evtCtx.requestBody)},
+	}
+
+	for _, field := range required {
+		if req.FindFieldDescriptorByName(field.name) == nil {
+			return UnsupportedEventSchemaError{Err: fmt.Errorf(
+				"destination message %q has no %q field required by the StripeEvent schema (see rpc/stripeevent/stripe_event.proto)",
+				req.GetMessageDescriptor().GetFullyQualifiedName(), field.name,
+			)}
+		}
+
+		if err := req.TrySetFieldByName(field.name, field.value); err != nil {
+			return FailedToPostError{Err: fmt.Errorf("setting field %q: %w", field.name, err)}
+		}
+	}
+
+	optional := []struct {
+		name  string
+		value interface{}
+	}{
+		{"created", stripeEvent.Created},
+		{"headers", evtCtx.requestHeaders},
+	}
+
+	for _, field := range optional {
+		if req.FindFieldDescriptorByName(field.name) == nil {
+			continue
+		}
+
+		if err := req.TrySetFieldByName(field.name, field.value); err != nil {
+			return FailedToPostError{Err: fmt.Errorf("setting field %q: %w", field.name, err)}
+		}
+	}
+
+	return nil
+}
+
+// UnsupportedEventSchemaError describes a gRPC destination whose request
+// message doesn't define a field stripe-cli needs to forward an event
+// without dropping data (see rpc/stripeevent/stripe_event.proto).
+type UnsupportedEventSchemaError struct {
+	Err error
+}
+
+func (e UnsupportedEventSchemaError) Error() string {
+	return e.Err.Error()
+}
+
+func splitGRPCMethod(path string) (service, method string, err error) {
+	path = strings.TrimPrefix(path, "/")
+
+	idx := strings.LastIndex(path, "/")
+	if idx <= 0 || idx == len(path)-1 {
+		return "", "", fmt.Errorf("path %q must be of the form /pkg.Service/Method", path)
+	}
+
+	return path[:idx], path[idx+1:], nil
+}
@@ -0,0 +1,132 @@
+package proxy
+
+import (
+	"testing"
+
+	"github.com/jhump/protoreflect/desc/builder"
+	"github.com/jhump/protoreflect/dynamic"
+)
+
+func TestSplitGRPCMethod(t *testing.T) {
+	cases := []struct {
+		name        string
+		path        string
+		wantService string
+		wantMethod  string
+		wantErr     bool
+	}{
+		{"valid path", "/pkg.Service/Method", "pkg.Service", "Method", false},
+		{"missing leading slash", "pkg.Service/Method", "pkg.Service", "Method", false},
+		{"no method", "/pkg.Service", "", "", true},
+		{"trailing slash", "/pkg.Service/", "", "", true},
+		{"empty path", "", "", "", true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			service, method, err := splitGRPCMethod(tc.path)
+
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error for path %q", tc.path)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if service != tc.wantService || method != tc.wantMethod {
+				t.Errorf("splitGRPCMethod(%q) = (%q, %q), want (%q, %q)", tc.path, service, method, tc.wantService, tc.wantMethod)
+			}
+		})
+	}
+}
+
+// stripeEventMessage builds a dynamic message with the given field names,
+// typed to match what populateStripeEventFields expects to set on them.
+func stripeEventMessage(t *testing.T, fields ...string) *dynamic.Message {
+	t.Helper()
+
+	msg := builder.NewMessage("StripeEvent")
+
+	for _, name := range fields {
+		var ft *builder.FieldType
+		switch name {
+		case "created":
+			ft = builder.FieldTypeInt64()
+		case "payload":
+			ft = builder.FieldTypeBytes()
+		default:
+			ft = builder.FieldTypeString()
+		}
+
+		msg.AddField(builder.NewField(name, ft))
+	}
+
+	md, err := msg.Build()
+	if err != nil {
+		t.Fatalf("building test message descriptor: %v", err)
+	}
+
+	return dynamic.NewMessage(md)
+}
+
+func TestPopulateStripeEventFields(t *testing.T) {
+	evtCtx := eventContext{
+		requestBody:    `{"id":"evt_123","type":"charge.succeeded","created":1700000000}`,
+		requestHeaders: map[string]string{"Stripe-Signature": "t=1,v1=abc"},
+	}
+
+	t.Run("populates required and optional fields when the schema has them", func(t *testing.T) {
+		req := stripeEventMessage(t, "event_id", "event_type", "payload", "created")
+
+		if err := populateStripeEventFields(req, evtCtx); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		id, err := req.TryGetFieldByName("event_id")
+		if err != nil || id != "evt_123" {
+			t.Errorf("event_id = %v, err = %v, want %q", id, err, "evt_123")
+		}
+
+		eventType, err := req.TryGetFieldByName("event_type")
+		if err != nil || eventType != "charge.succeeded" {
+			t.Errorf("event_type = %v, err = %v, want %q", eventType, err, "charge.succeeded")
+		}
+
+		created, err := req.TryGetFieldByName("created")
+		if err != nil || created != int64(1700000000) {
+			t.Errorf("created = %v, err = %v, want %d", created, err, 1700000000)
+		}
+	})
+
+	t.Run("skips optional fields the schema doesn't define", func(t *testing.T) {
+		req := stripeEventMessage(t, "event_id", "event_type", "payload")
+
+		if err := populateStripeEventFields(req, evtCtx); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	})
+
+	t.Run("rejects a schema missing a required field", func(t *testing.T) {
+		req := stripeEventMessage(t, "event_id", "payload")
+
+		err := populateStripeEventFields(req, evtCtx)
+		if _, ok := err.(UnsupportedEventSchemaError); !ok {
+			t.Fatalf("expected an UnsupportedEventSchemaError, got %v", err)
+		}
+	})
+
+	t.Run("rejects a malformed Stripe event payload", func(t *testing.T) {
+		req := stripeEventMessage(t, "event_id", "event_type", "payload")
+
+		malformed := eventContext{requestBody: `not json`}
+
+		err := populateStripeEventFields(req, malformed)
+		if _, ok := err.(EventPayloadDecodeError); !ok {
+			t.Fatalf("expected an EventPayloadDecodeError, got %v", err)
+		}
+	})
+}
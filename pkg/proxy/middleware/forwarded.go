@@ -0,0 +1,38 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+)
+
+// ForwardedHeaders returns request middleware that adds `X-Forwarded-For`,
+// `X-Forwarded-Proto`, `X-Forwarded-Host`, and a standards-based `Forwarded`
+// header (RFC 7239) describing the original Stripe-to-local-endpoint hop, so
+// a local handler behind this proxy can see the original ingress info.
+// Empty arguments are omitted from both the legacy and RFC 7239 headers.
+func ForwardedHeaders(forwardedFor, proto, host string) func(*http.Request) (*http.Request, error) {
+	return func(req *http.Request) (*http.Request, error) {
+		var forwarded []string
+
+		if forwardedFor != "" {
+			req.Header.Set("X-Forwarded-For", forwardedFor)
+			forwarded = append(forwarded, `for="`+forwardedFor+`"`)
+		}
+
+		if host != "" {
+			req.Header.Set("X-Forwarded-Host", host)
+			forwarded = append(forwarded, `host="`+host+`"`)
+		}
+
+		if proto != "" {
+			req.Header.Set("X-Forwarded-Proto", proto)
+			forwarded = append(forwarded, `proto=`+proto)
+		}
+
+		if len(forwarded) > 0 {
+			req.Header.Set("Forwarded", strings.Join(forwarded, "; "))
+		}
+
+		return req, nil
+	}
+}
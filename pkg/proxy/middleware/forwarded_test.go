@@ -0,0 +1,76 @@
+package middleware
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestForwardedHeaders(t *testing.T) {
+	cases := []struct {
+		name                string
+		forwardedFor        string
+		proto               string
+		host                string
+		wantXForwardedFor   string
+		wantXForwardedProto string
+		wantXForwardedHost  string
+		wantForwarded       string
+	}{
+		{
+			name:                "all arguments set",
+			forwardedFor:        "203.0.113.1",
+			proto:               "https",
+			host:                "example.com",
+			wantXForwardedFor:   "203.0.113.1",
+			wantXForwardedProto: "https",
+			wantXForwardedHost:  "example.com",
+			wantForwarded:       `for="203.0.113.1"; host="example.com"; proto=https`,
+		},
+		{
+			name:          "no arguments set",
+			wantForwarded: "",
+		},
+		{
+			name:                "only proto set",
+			proto:               "http",
+			wantXForwardedProto: "http",
+			wantForwarded:       "proto=http",
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			req, err := http.NewRequest(http.MethodPost, "http://localhost", nil)
+			if err != nil {
+				t.Fatalf("unexpected error building request: %v", err)
+			}
+
+			mw := ForwardedHeaders(tc.forwardedFor, tc.proto, tc.host)
+
+			got, err := mw(req)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got != req {
+				t.Fatal("expected the middleware to return the same request it was given")
+			}
+
+			if h := req.Header.Get("X-Forwarded-For"); h != tc.wantXForwardedFor {
+				t.Errorf("X-Forwarded-For = %q, want %q", h, tc.wantXForwardedFor)
+			}
+
+			if h := req.Header.Get("X-Forwarded-Proto"); h != tc.wantXForwardedProto {
+				t.Errorf("X-Forwarded-Proto = %q, want %q", h, tc.wantXForwardedProto)
+			}
+
+			if h := req.Header.Get("X-Forwarded-Host"); h != tc.wantXForwardedHost {
+				t.Errorf("X-Forwarded-Host = %q, want %q", h, tc.wantXForwardedHost)
+			}
+
+			if h := req.Header.Get("Forwarded"); h != tc.wantForwarded {
+				t.Errorf("Forwarded = %q, want %q", h, tc.wantForwarded)
+			}
+		})
+	}
+}
@@ -0,0 +1,20 @@
+package middleware
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// OpenTelemetry returns request middleware that injects the span context
+// carried on the outgoing request (req.Context()) into its headers using
+// the globally configured OpenTelemetry text map propagator, e.g. as a W3C
+// `traceparent` header. Install it with `otel.SetTextMapPropagator` before
+// use if the default no-op propagator hasn't already been replaced.
+func OpenTelemetry() func(*http.Request) (*http.Request, error) {
+	return func(req *http.Request) (*http.Request, error) {
+		otel.GetTextMapPropagator().Inject(req.Context(), propagation.HeaderCarrier(req.Header))
+		return req, nil
+	}
+}
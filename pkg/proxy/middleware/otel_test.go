@@ -0,0 +1,49 @@
+package middleware
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestOpenTelemetryInjectsTraceContext(t *testing.T) {
+	prev := otel.GetTextMapPropagator()
+	defer otel.SetTextMapPropagator(prev)
+
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1},
+		SpanID:     trace.SpanID{1},
+		TraceFlags: trace.FlagsSampled,
+	})
+
+	req, err := http.NewRequest(http.MethodPost, "http://localhost", nil)
+	if err != nil {
+		t.Fatalf("unexpected error building request: %v", err)
+	}
+
+	req = req.WithContext(trace.ContextWithSpanContext(req.Context(), sc))
+
+	got, err := OpenTelemetry()(req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if got != req {
+		t.Fatal("expected the middleware to return the same request it was given")
+	}
+
+	traceparent := req.Header.Get("traceparent")
+	if traceparent == "" {
+		t.Fatal("expected a traceparent header to be injected")
+	}
+
+	if !strings.Contains(traceparent, sc.TraceID().String()) {
+		t.Errorf("traceparent = %q, want it to contain trace ID %q", traceparent, sc.TraceID().String())
+	}
+}
@@ -0,0 +1,141 @@
+package proxy
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/nats-io/nats.go"
+
+	"github.com/stripe/stripe-cli/pkg/websocket"
+)
+
+// NATSForwarder publishes Stripe events to a NATS subject, resolved from a
+// `nats://host:port/subject` forward-to URI. The connection is established
+// on the first Send call and reused across subsequent ones.
+type NATSForwarder struct {
+	url     string
+	subject string
+
+	cfg *EndpointConfig
+
+	mu sync.Mutex
+	nc *nats.Conn
+}
+
+// NewNATSForwarder returns a Forwarder that publishes to the subject named
+// by the given `nats://` forward-to URI.
+func NewNATSForwarder(rawURL string, cfg *EndpointConfig) (*NATSForwarder, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("parsing NATS forward-to URI: %w", err)
+	}
+
+	subject := strings.TrimPrefix(u.Path, "/")
+	if subject == "" {
+		return nil, fmt.Errorf("NATS forward-to URI %q is missing a /subject path", rawURL)
+	}
+
+	if cfg == nil {
+		cfg = &EndpointConfig{}
+	}
+
+	return &NATSForwarder{
+		url:     fmt.Sprintf("nats://%s", u.Host),
+		subject: subject,
+		cfg:     cfg,
+	}, nil
+}
+
+// Send publishes evtCtx's raw body to n.subject, with the event's id and
+// type attached as NATS message headers alongside the original webhook
+// headers. NATS is fire-and-forget, so the returned Response carries no
+// status code.
+func (n *NATSForwarder) Send(ctx context.Context, evtCtx eventContext) (Response, error) {
+	nc, err := n.connection()
+	if err != nil {
+		n.reportError(err)
+		return Response{}, err
+	}
+
+	id, eventType, err := stripeEventIDAndType(evtCtx)
+	if err != nil {
+		n.reportError(err)
+		return Response{}, err
+	}
+
+	msg := &nats.Msg{
+		Subject: n.subject,
+		Data:    []byte(evtCtx.requestBody),
+		Header:  nats.Header{},
+	}
+
+	for k, v := range evtCtx.requestHeaders {
+		msg.Header.Set(k, v)
+	}
+
+	msg.Header.Set("Stripe-Event-Id", id)
+	msg.Header.Set("Stripe-Event-Type", eventType)
+
+	if err := nc.PublishMsg(msg); err != nil {
+		wrapped := FailedToPostError{Err: err}
+		n.reportError(wrapped)
+		return Response{}, wrapped
+	}
+
+	if err := nc.FlushWithContext(ctx); err != nil {
+		wrapped := FailedToPostError{Err: err}
+		n.reportError(wrapped)
+		return Response{}, wrapped
+	}
+
+	return Response{Status: Status{Detail: "published"}}, nil
+}
+
+// connection returns n's cached NATS connection, dialing one on the first
+// call (or if the cached one has since closed) and reusing it thereafter.
+func (n *NATSForwarder) connection() (*nats.Conn, error) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.nc != nil && !n.nc.IsClosed() {
+		return n.nc, nil
+	}
+
+	nc, err := nats.Connect(n.url)
+	if err != nil {
+		return nil, FailedToPostError{Err: fmt.Errorf("connecting to NATS at %q: %w", n.url, err)}
+	}
+
+	n.nc = nc
+
+	return n.nc, nil
+}
+
+// Close implements Forwarder for NATSForwarder, closing the cached
+// connection if one was ever established.
+func (n *NATSForwarder) Close() error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.nc == nil {
+		return nil
+	}
+
+	n.nc.Close()
+	n.nc = nil
+
+	return nil
+}
+
+// reportError pushes err to n.cfg.OutCh so a failed NATS publish is visible
+// to the UI the same way a failed HTTP forward is.
+func (n *NATSForwarder) reportError(err error) {
+	if n.cfg.OutCh == nil {
+		return
+	}
+
+	n.cfg.OutCh <- websocket.ErrorElement{Error: err}
+}
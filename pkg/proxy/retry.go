@@ -0,0 +1,114 @@
+package proxy
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+//
+// Private functions
+//
+
+// shouldRetryPost reports whether a forward attempt should be retried given
+// the response (if any) and error returned by client.Do, and how long to
+// wait before retrying. It never retries once ctx itself has been canceled
+// or its deadline has expired, since the error in that case is the context
+// being done rather than a transient failure worth retrying.
+func (c *EndpointClient) shouldRetryPost(ctx context.Context, attempt int, resp *http.Response, err error) (bool, time.Duration) {
+	if attempt >= c.cfg.MaxRetries {
+		return false, 0
+	}
+
+	if ctx.Err() != nil {
+		return false, 0
+	}
+
+	if err != nil {
+		return true, c.backoff(attempt)
+	}
+
+	if resp == nil || !c.cfg.RetryableStatus(resp.StatusCode) {
+		return false, 0
+	}
+
+	if wait, ok := retryAfterDelay(resp); ok {
+		return true, wait
+	}
+
+	return true, c.backoff(attempt)
+}
+
+// backoff returns the jittered exponential delay before the given retry
+// attempt (0-indexed): InitialBackoff * 2^attempt, capped at
+// EndpointConfig.MaxBackoff, then scaled by a random factor in [0.5, 1.5).
+// The result can therefore exceed MaxBackoff by up to half of it.
+func (c *EndpointClient) backoff(attempt int) time.Duration {
+	delay := time.Duration(float64(c.cfg.InitialBackoff) * math.Pow(2, float64(attempt)))
+	if delay <= 0 || delay > c.cfg.MaxBackoff {
+		delay = c.cfg.MaxBackoff
+	}
+
+	jitter := 0.5 + rand.Float64() // nolint:gosec
+
+	return time.Duration(float64(delay) * jitter)
+}
+
+// defaultRetryableStatus is the default EndpointConfig.RetryableStatus.
+func defaultRetryableStatus(status int) bool {
+	switch status {
+	case http.StatusRequestTimeout,
+		http.StatusTooEarly,
+		http.StatusTooManyRequests,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout:
+		return true
+	default:
+		return false
+	}
+}
+
+// retryAfterDelay reads the `Retry-After` header from a 429 or 503 response,
+// returning the delay it specifies (as either a number of seconds or an
+// HTTP-date) and whether one was present.
+func retryAfterDelay(resp *http.Response) (time.Duration, bool) {
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusServiceUnavailable {
+		return 0, false
+	}
+
+	v := resp.Header.Get("Retry-After")
+	if v == "" {
+		return 0, false
+	}
+
+	if secs, err := strconv.Atoi(v); err == nil {
+		return time.Duration(secs) * time.Second, true
+	}
+
+	if t, err := http.ParseTime(v); err == nil {
+		return time.Until(t), true
+	}
+
+	return 0, false
+}
+
+// idempotencyKey derives an Idempotency-Key for a retried request from the
+// Stripe event ID embedded in evtCtx's request body, so the local handler
+// can dedupe repeated deliveries of the same event.
+func idempotencyKey(evtCtx eventContext) string {
+	var stripeEvent struct {
+		ID string `json:"id"`
+	}
+
+	if err := json.Unmarshal([]byte(evtCtx.requestBody), &stripeEvent); err != nil {
+		return ""
+	}
+
+	return stripeEvent.ID
+}
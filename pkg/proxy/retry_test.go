@@ -0,0 +1,139 @@
+package proxy
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestShouldRetryPost(t *testing.T) {
+	client := &EndpointClient{cfg: &EndpointConfig{
+		MaxRetries:      2,
+		InitialBackoff:  time.Millisecond,
+		MaxBackoff:      time.Millisecond,
+		RetryableStatus: defaultRetryableStatus,
+	}}
+
+	errConnRefused := errors.New("connection refused")
+
+	t.Run("retries a network error", func(t *testing.T) {
+		retry, _ := client.shouldRetryPost(context.Background(), 0, nil, errConnRefused)
+		if !retry {
+			t.Fatal("expected a network error to be retried")
+		}
+	})
+
+	t.Run("retries a retryable status", func(t *testing.T) {
+		resp := &http.Response{StatusCode: http.StatusServiceUnavailable, Header: http.Header{}}
+
+		retry, _ := client.shouldRetryPost(context.Background(), 0, resp, nil)
+		if !retry {
+			t.Fatal("expected a 503 to be retried")
+		}
+	})
+
+	t.Run("does not retry a non-retryable status", func(t *testing.T) {
+		resp := &http.Response{StatusCode: http.StatusBadRequest, Header: http.Header{}}
+
+		retry, _ := client.shouldRetryPost(context.Background(), 0, resp, nil)
+		if retry {
+			t.Fatal("expected a 400 not to be retried")
+		}
+	})
+
+	t.Run("does not retry once MaxRetries is exhausted", func(t *testing.T) {
+		retry, _ := client.shouldRetryPost(context.Background(), client.cfg.MaxRetries, nil, errConnRefused)
+		if retry {
+			t.Fatal("expected an exhausted retry budget not to retry")
+		}
+	})
+
+	t.Run("does not retry once the context is canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		retry, _ := client.shouldRetryPost(ctx, 0, nil, errConnRefused)
+		if retry {
+			t.Fatal("expected a canceled context not to retry")
+		}
+	})
+
+	t.Run("honors Retry-After on a 429", func(t *testing.T) {
+		resp := &http.Response{
+			StatusCode: http.StatusTooManyRequests,
+			Header:     http.Header{"Retry-After": []string{"2"}},
+		}
+
+		retry, wait := client.shouldRetryPost(context.Background(), 0, resp, nil)
+		if !retry {
+			t.Fatal("expected a 429 to be retried")
+		}
+
+		if wait != 2*time.Second {
+			t.Fatalf("wait = %s, want 2s", wait)
+		}
+	})
+}
+
+func TestBackoff(t *testing.T) {
+	client := &EndpointClient{cfg: &EndpointConfig{
+		InitialBackoff: 100 * time.Millisecond,
+		MaxBackoff:     time.Second,
+	}}
+
+	for attempt := 0; attempt < 5; attempt++ {
+		delay := client.backoff(attempt)
+
+		// The un-jittered delay is InitialBackoff * 2^attempt capped at
+		// MaxBackoff; jitter then scales it by a factor in [0.5, 1.5).
+		base := client.cfg.InitialBackoff << attempt
+		if base > client.cfg.MaxBackoff {
+			base = client.cfg.MaxBackoff
+		}
+
+		if delay < base/2 || delay > base*3/2 {
+			t.Fatalf("attempt %d: delay = %s, want within [%s, %s]", attempt, delay, base/2, base*3/2)
+		}
+	}
+}
+
+func TestDefaultRetryableStatus(t *testing.T) {
+	cases := map[int]bool{
+		http.StatusOK:                  false,
+		http.StatusBadRequest:          false,
+		http.StatusTooManyRequests:     true,
+		http.StatusServiceUnavailable:  true,
+		http.StatusInternalServerError: true,
+		http.StatusGatewayTimeout:      true,
+	}
+
+	for status, want := range cases {
+		if got := defaultRetryableStatus(status); got != want {
+			t.Errorf("defaultRetryableStatus(%d) = %v, want %v", status, got, want)
+		}
+	}
+}
+
+func TestIdempotencyKey(t *testing.T) {
+	cases := []struct {
+		name string
+		body string
+		want string
+	}{
+		{"valid event", `{"id":"evt_123","type":"charge.succeeded"}`, "evt_123"},
+		{"invalid JSON", `not json`, ""},
+		{"missing id", `{"type":"charge.succeeded"}`, ""},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			evtCtx := eventContext{requestBody: tc.body}
+
+			if got := idempotencyKey(evtCtx); got != tc.want {
+				t.Errorf("idempotencyKey() = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}